@@ -0,0 +1,249 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// RateLimit defines the desired limitador limit
+type RateLimit struct {
+	Conditions []string `json:"conditions"`
+	MaxValue   int      `json:"max_value"`
+	Namespace  string   `json:"namespace"`
+	Seconds    int      `json:"seconds"`
+	Variables  []string `json:"variables"`
+}
+
+// Redis holds the storage config for a regular (non-cached) redis backend. TLS is controlled
+// solely by the URL's scheme: use a rediss:// URL in the Secret referenced by ConfigSecretRef.
+type Redis struct {
+	// ConfigSecretRef refers to the Secret holding the Redis URL.
+	// The Secret must be in the same namespace as limitador.
+	// +optional
+	ConfigSecretRef *v1.ObjectReference `json:"configSecretRef,omitempty"`
+}
+
+// RedisCachedOptions defines the options of the redis cached storage
+type RedisCachedOptions struct {
+	// +optional
+	TTL *int `json:"ttl,omitempty"`
+	// +optional
+	Ratio *int `json:"ratio,omitempty"`
+	// +optional
+	FlushPeriod *int `json:"flush-period,omitempty"`
+	// +optional
+	MaxCached *int `json:"max-cached,omitempty"`
+}
+
+// RedisCached holds the storage config for a redis backend with an in-memory cache. TLS is
+// controlled solely by the URL's scheme: use a rediss:// URL in the Secret referenced by
+// ConfigSecretRef.
+type RedisCached struct {
+	// ConfigSecretRef refers to the Secret holding the Redis URL.
+	// The Secret must be in the same namespace as limitador.
+	// +optional
+	ConfigSecretRef *v1.ObjectReference `json:"configSecretRef,omitempty"`
+
+	// +optional
+	Options *RedisCachedOptions `json:"options,omitempty"`
+}
+
+// Memcached holds the storage config for a memcached backend
+type Memcached struct {
+	// ConfigSecretRef refers to the Secret holding the Memcached URL.
+	// The Secret must be in the same namespace as limitador.
+	// +optional
+	ConfigSecretRef *v1.ObjectReference `json:"configSecretRef,omitempty"`
+
+	// +optional
+	FlushPeriod *int `json:"flush-period,omitempty"`
+	// +optional
+	MaxCached *int `json:"max-cached,omitempty"`
+}
+
+// DiskSpec holds the storage config for the disk backend
+type DiskSpec struct {
+	// +optional
+	Persistent bool `json:"persistent,omitempty"`
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
+	// +optional
+	Optimize string `json:"optimize,omitempty"`
+}
+
+// Storage holds the options for the rate limit counters database
+type Storage struct {
+	// +optional
+	Redis *Redis `json:"redis,omitempty"`
+
+	// +optional
+	RedisCached *RedisCached `json:"redis-cached,omitempty"`
+
+	// +optional
+	Memcached *Memcached `json:"memcached,omitempty"`
+
+	// +optional
+	Disk *DiskSpec `json:"disk,omitempty"`
+}
+
+// PodDisruptionBudgetType defines the disruption budget for the limitador deployment
+type PodDisruptionBudgetType struct {
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// LimitadorSpec defines the desired state of Limitador
+type LimitadorSpec struct {
+	// +optional
+	Replicas *int `json:"replicas,omitempty"`
+
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// +optional
+	ResourceRequirements *v1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetType `json:"pdb,omitempty"`
+
+	// +optional
+	Storage *Storage `json:"storage,omitempty"`
+
+	// +optional
+	Limits []RateLimit `json:"limits,omitempty"`
+}
+
+// LimitadorStatus defines the observed state of Limitador
+type LimitadorStatus struct {
+	// +optional
+	Conditions []v1.NodeCondition `json:"conditions,omitempty"`
+
+	// +optional
+	Service *LimitadorService `json:"service,omitempty"`
+
+	// Resources aggregates the observed state of every resource owned by this Limitador, so
+	// that its overall health can be read off a single `kubectl get limitador -o yaml`.
+	// +optional
+	Resources *ResourcesStatus `json:"resources,omitempty"`
+}
+
+// DeploymentStatusSummary is the observed state of the owned Deployment.
+type DeploymentStatusSummary struct {
+	Image             string   `json:"image,omitempty"`
+	Replicas          int32    `json:"replicas,omitempty"`
+	ReadyReplicas     int32    `json:"readyReplicas,omitempty"`
+	AvailableReplicas int32    `json:"availableReplicas,omitempty"`
+	UpdatedReplicas   int32    `json:"updatedReplicas,omitempty"`
+	Conditions        []string `json:"conditions,omitempty"`
+}
+
+// ServiceStatusSummary is the observed state of the owned Service.
+type ServiceStatusSummary struct {
+	ClusterIP string  `json:"clusterIP,omitempty"`
+	Ports     []int32 `json:"ports,omitempty"`
+}
+
+// ConfigMapStatusSummary is the observed state of the owned limits ConfigMap.
+type ConfigMapStatusSummary struct {
+	DataHash string `json:"dataHash,omitempty"`
+}
+
+// PodDisruptionBudgetStatusSummary is the observed state of the owned PodDisruptionBudget.
+type PodDisruptionBudgetStatusSummary struct {
+	DisruptionsAllowed int32 `json:"disruptionsAllowed,omitempty"`
+}
+
+// PVCStatusSummary is the observed state of the owned PersistentVolumeClaim.
+type PVCStatusSummary struct {
+	Phase      string `json:"phase,omitempty"`
+	VolumeName string `json:"volumeName,omitempty"`
+}
+
+// ContainerStatusSummary is the observed state of a single container within a Pod.
+type ContainerStatusSummary struct {
+	Name         string `json:"name"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+}
+
+// PodStatusSummary is the observed state of a single owned Pod.
+type PodStatusSummary struct {
+	Name         string                   `json:"name"`
+	Phase        string                   `json:"phase"`
+	RestartCount int32                    `json:"restartCount"`
+	Containers   []ContainerStatusSummary `json:"containers,omitempty"`
+}
+
+// ResourcesStatus is the aggregated state of every resource owned by a Limitador.
+type ResourcesStatus struct {
+	// +optional
+	Deployment *DeploymentStatusSummary `json:"deployment,omitempty"`
+	// +optional
+	Service *ServiceStatusSummary `json:"service,omitempty"`
+	// +optional
+	ConfigMap *ConfigMapStatusSummary `json:"configMap,omitempty"`
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetStatusSummary `json:"podDisruptionBudget,omitempty"`
+	// +optional
+	PVC *PVCStatusSummary `json:"pvc,omitempty"`
+	// +optional
+	Pods []PodStatusSummary `json:"pods,omitempty"`
+}
+
+// LimitadorService holds the status information about the limitador service
+type LimitadorService struct {
+	Host  string `json:"host"`
+	Ports Ports  `json:"ports"`
+}
+
+// Ports holds the status information about the limitador service ports
+type Ports struct {
+	GRPC int32 `json:"grpc"`
+	HTTP int32 `json:"http"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// Limitador is the Schema for the limitadors API
+type Limitador struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LimitadorSpec   `json:"spec,omitempty"`
+	Status LimitadorStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LimitadorList contains a list of Limitador
+type LimitadorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Limitador `json:"items"`
+}
@@ -29,7 +29,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/yaml"
 
 	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
@@ -200,6 +205,7 @@ func (r *LimitadorReconciler) reconcileDeployment(ctx context.Context, limitador
 		reconcilers.DeploymentVolumesMutator,
 		reconcilers.DeploymentVolumeMountsMutator,
 		reconcilers.DeploymentEnvMutator,
+		reconcilers.DeploymentAnnotationsMutator,
 	)
 
 	deployment := limitador.Deployment(limitadorObj, deploymentOptions)
@@ -302,7 +308,9 @@ func mutateLimitsConfigMap(existingObj, desiredObj client.Object) (bool, error)
 
 	updated := false
 
-	// Limits in limitador.LimitadorConfigFileName field
+	// Limits in limitador.LimitadorConfigFileName field. desired.Data already holds the canonical,
+	// order-independent serialization built by limitador.LimitsConfigMap, so existing only needs to
+	// be canonicalized here for the comparison.
 	var desiredLimits []limitadorv1alpha1.RateLimit
 	err := yaml.Unmarshal([]byte(desired.Data[limitador.LimitadorConfigFileName]), &desiredLimits)
 	if err != nil {
@@ -317,12 +325,19 @@ func mutateLimitsConfigMap(existingObj, desiredObj client.Object) (bool, error)
 		existingLimits = nil
 	}
 
-	// TODO(eastizle): deepEqual returns false when the order in the list is not equal.
-	// Improvement would be checking to equality of slices ignoring order
-	if !reflect.DeepEqual(desiredLimits, existingLimits) {
+	if !reflect.DeepEqual(limitador.CanonicalizeRateLimits(desiredLimits), limitador.CanonicalizeRateLimits(existingLimits)) {
 		existing.Data[limitador.LimitadorConfigFileName] = desired.Data[limitador.LimitadorConfigFileName]
 		updated = true
 	}
+
+	if existing.Annotations[limitador.LimitsHashAnnotation] != desired.Annotations[limitador.LimitsHashAnnotation] {
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[limitador.LimitsHashAnnotation] = desired.Annotations[limitador.LimitsHashAnnotation]
+		updated = true
+	}
+
 	return updated, nil
 }
 
@@ -338,46 +353,141 @@ func (r *LimitadorReconciler) getDeploymentOptions(ctx context.Context, limObj *
 	deploymentOptions.VolumeMounts = limitador.DeploymentVolumeMounts(deploymentStorageOptions)
 	deploymentOptions.Volumes = limitador.DeploymentVolumes(limObj, deploymentStorageOptions)
 	deploymentOptions.DeploymentStrategy = deploymentStorageOptions.DeploymentStrategy
-	deploymentOptions.EnvVar, err = r.getDeploymentEnvVar(limObj)
+	deploymentOptions.EnvVar, err = r.getDeploymentEnvVar(ctx, limObj)
 	if err != nil {
 		return deploymentOptions, err
 	}
 
+	limitsHash, err := limitador.LimitsFingerprint(limObj.Spec.Limits)
+	if err != nil {
+		return deploymentOptions, err
+	}
+
+	deploymentOptions.PodAnnotations = map[string]string{limitador.LimitsHashAnnotation: limitsHash}
+	for k, v := range deploymentStorageOptions.PodAnnotations {
+		deploymentOptions.PodAnnotations[k] = v
+	}
+
 	return deploymentOptions, nil
 }
 
+// getDeploymentStorageOptions resolves the configured storage backend from the pluggable
+// registry in pkg/limitador. Backends without a registry entry (currently just Disk, which needs
+// no external config to validate) are special-cased; everything else, including out-of-tree
+// backends registered by other operators, is handled generically.
 func (r *LimitadorReconciler) getDeploymentStorageOptions(ctx context.Context, limObj *limitadorv1alpha1.Limitador) (limitador.DeploymentStorageOptions, error) {
-	if limObj.Spec.Storage != nil {
-		if limObj.Spec.Storage.Redis != nil {
-			return limitador.RedisDeploymentOptions(ctx, r.Client(), limObj.Namespace, *limObj.Spec.Storage.Redis)
+	if backend, spec, ok := limitador.ResolveStorageBackend(limObj.Spec.Storage); ok {
+		if err := backend.Validate(spec); err != nil {
+			return limitador.DeploymentStorageOptions{}, err
 		}
+		return backend.DeploymentOptions(ctx, r.Client(), limObj.Namespace, spec)
+	}
 
-		if limObj.Spec.Storage.RedisCached != nil {
-			return limitador.RedisCachedDeploymentOptions(ctx, r.Client(), limObj.Namespace, *limObj.Spec.Storage.RedisCached)
-		}
+	if limObj.Spec.Storage != nil && limObj.Spec.Storage.Disk != nil {
+		return limitador.DiskDeploymentOptions(limObj, *limObj.Spec.Storage.Disk)
+	}
 
-		if limObj.Spec.Storage.Disk != nil {
-			return limitador.DiskDeploymentOptions(limObj, *limObj.Spec.Storage.Disk)
-		}
+	// if none of the storage fields are set, fallback to InMemory
+	return limitador.InMemoryDeploymentOptions()
+}
 
-		// if all of them are nil, fallback to InMemory
+func (r *LimitadorReconciler) getDeploymentEnvVar(ctx context.Context, limObj *limitadorv1alpha1.Limitador) ([]v1.EnvVar, error) {
+	if backend, spec, ok := limitador.ResolveStorageBackend(limObj.Spec.Storage); ok {
+		return backend.EnvVars(ctx, r.Client(), limObj.Namespace, spec)
 	}
 
-	return limitador.InMemoryDeploymentOptions()
+	return nil, nil
 }
 
-func (r *LimitadorReconciler) getDeploymentEnvVar(limObj *limitadorv1alpha1.Limitador) ([]v1.EnvVar, error) {
-	if limObj.Spec.Storage != nil {
-		if limObj.Spec.Storage.Redis != nil {
-			return limitador.DeploymentEnvVar(limObj.Spec.Storage.Redis.ConfigSecretRef)
-		}
+// findLimitadorsForRedisSecret maps a watched Secret back to the reconcile Requests for every
+// Limitador in the same namespace whose Redis, RedisCached or Memcached storage points at it, so
+// that rotating the Secret's URL is picked up without waiting for the next spec change.
+func (r *LimitadorReconciler) findLimitadorsForRedisSecret(ctx context.Context, secretObj client.Object) []reconcile.Request {
+	limitadorList := &limitadorv1alpha1.LimitadorList{}
+	if err := r.Client().List(ctx, limitadorList, client.InNamespace(secretObj.GetNamespace())); err != nil {
+		return nil
+	}
 
-		if limObj.Spec.Storage.RedisCached != nil {
-			return limitador.DeploymentEnvVar(limObj.Spec.Storage.RedisCached.ConfigSecretRef)
+	requests := make([]reconcile.Request, 0)
+	for i := range limitadorList.Items {
+		limitadorObj := limitadorList.Items[i]
+		if referencesSecret(limitadorObj.Spec.Storage, secretObj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: limitadorObj.Name, Namespace: limitadorObj.Namespace},
+			})
 		}
 	}
 
-	return nil, nil
+	return requests
+}
+
+func referencesSecret(storage *limitadorv1alpha1.Storage, secretName string) bool {
+	if storage == nil {
+		return false
+	}
+
+	if storage.Redis != nil && redisConfigSecretMatches(storage.Redis.ConfigSecretRef, secretName) {
+		return true
+	}
+
+	if storage.RedisCached != nil && redisConfigSecretMatches(storage.RedisCached.ConfigSecretRef, secretName) {
+		return true
+	}
+
+	if storage.Memcached != nil && storage.Memcached.ConfigSecretRef != nil && storage.Memcached.ConfigSecretRef.Name == secretName {
+		return true
+	}
+
+	return false
+}
+
+func redisConfigSecretMatches(secretRef *v1.ObjectReference, secretName string) bool {
+	return secretRef != nil && secretRef.Name == secretName
+}
+
+// findLimitadorForOwnedObject maps a Pod or Service carrying limitador.DeploymentLabels back to
+// the reconcile Request for the Limitador that owns it, so status can be refreshed even though
+// Pods (owned by the ReplicaSet, not the Limitador) don't have a usable owner reference chain.
+func findLimitadorForOwnedObject(_ context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()["limitador"]
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}},
+	}
+}
+
+// podStatusChangedPredicate only lets Pod events through when something status-relevant actually
+// changed (phase, readiness or a container restart), so churn like periodic resync events doesn't
+// requeue a Limitador that has nothing new to report. It thins the event stream; Reconcile still
+// runs reconcileSpec before reconcileStatus for every request that does get through, since
+// reconcile.Request carries no information about which watch triggered it and reconcileSpec is
+// itself a no-op write whenever the owned resources already match the desired state.
+var podStatusChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return true },
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*v1.Pod)
+		if !ok {
+			return true
+		}
+		newPod, ok := e.ObjectNew.(*v1.Pod)
+		if !ok {
+			return true
+		}
+		return podStatusFingerprint(oldPod) != podStatusFingerprint(newPod)
+	},
+}
+
+func podStatusFingerprint(pod *v1.Pod) string {
+	fingerprint := string(pod.Status.Phase)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		fingerprint += fmt.Sprintf("|%s:%t:%d", containerStatus.Name, containerStatus.Ready, containerStatus.RestartCount)
+	}
+	return fingerprint
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -387,5 +497,8 @@ func (r *LimitadorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&v1.ConfigMap{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
+		Watches(&v1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.findLimitadorsForRedisSecret)).
+		Watches(&v1.Service{}, handler.EnqueueRequestsFromMapFunc(findLimitadorForOwnedObject)).
+		Watches(&v1.Pod{}, handler.EnqueueRequestsFromMapFunc(findLimitadorForOwnedObject), ctrlbuilder.WithPredicates(podStatusChangedPredicate)).
 		Complete(r)
 }
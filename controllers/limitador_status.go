@@ -0,0 +1,287 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"github.com/kuadrant/limitador-operator/pkg/limitador"
+)
+
+// reconcileStatus publishes the observed state of the Limitador object and every resource it owns
+// onto Status.Resources, refreshes the Ready condition and the Service's host/ports, giving users
+// a single place to look at the health of the bundle.
+func (r *LimitadorReconciler) reconcileStatus(ctx context.Context, limitadorObj *limitadorv1alpha1.Limitador, specErr error) (ctrl.Result, error) {
+	newStatus := limitadorObj.Status.DeepCopy()
+
+	resources, service, err := r.gatherResourcesStatus(ctx, limitadorObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	newStatus.Resources = resources
+	newStatus.Service = limitadorServiceStatus(service)
+	newStatus.Conditions = readyCondition(limitadorObj.Status.Conditions, specErr, resources.Deployment, limitadorObj.Spec.Replicas)
+
+	if equalResourcesStatus(limitadorObj.Status.Resources, newStatus.Resources) &&
+		reflect.DeepEqual(limitadorObj.Status.Service, newStatus.Service) &&
+		reflect.DeepEqual(limitadorObj.Status.Conditions, newStatus.Conditions) {
+		return ctrl.Result{}, nil
+	}
+
+	limitadorObj.Status = *newStatus
+	if err := r.Client().Status().Update(ctx, limitadorObj); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *LimitadorReconciler) gatherResourcesStatus(ctx context.Context, limitadorObj *limitadorv1alpha1.Limitador) (*limitadorv1alpha1.ResourcesStatus, *v1.Service, error) {
+	resources := &limitadorv1alpha1.ResourcesStatus{}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.getOwnedResource(ctx, limitadorObj.Namespace, limitador.DeploymentName(limitadorObj), deployment); err != nil {
+		return nil, nil, err
+	}
+	if deployment.CreationTimestamp.IsZero() {
+		resources.Deployment = nil
+	} else {
+		resources.Deployment = deploymentStatusSummary(deployment)
+	}
+
+	service := &v1.Service{}
+	if err := r.getOwnedResource(ctx, limitadorObj.Namespace, limitador.ServiceName(limitadorObj), service); err != nil {
+		return nil, nil, err
+	}
+	if service.CreationTimestamp.IsZero() {
+		service = nil
+	} else {
+		resources.Service = serviceStatusSummary(service)
+	}
+
+	configMap := &v1.ConfigMap{}
+	if err := r.getOwnedResource(ctx, limitadorObj.Namespace, limitador.LimitsConfigMapName(limitadorObj), configMap); err != nil {
+		return nil, nil, err
+	}
+	if !configMap.CreationTimestamp.IsZero() {
+		resources.ConfigMap = &limitadorv1alpha1.ConfigMapStatusSummary{
+			DataHash: hashConfigMapData(configMap.Data[limitador.LimitadorConfigFileName]),
+		}
+	}
+
+	if limitadorObj.Spec.PodDisruptionBudget != nil {
+		pdb := &policyv1.PodDisruptionBudget{}
+		if err := r.getOwnedResource(ctx, limitadorObj.Namespace, limitador.PodDisruptionBudgetName(limitadorObj), pdb); err != nil {
+			return nil, nil, err
+		}
+		if !pdb.CreationTimestamp.IsZero() {
+			resources.PodDisruptionBudget = &limitadorv1alpha1.PodDisruptionBudgetStatusSummary{
+				DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			}
+		}
+	}
+
+	if limitadorObj.Spec.Storage != nil && limitadorObj.Spec.Storage.Disk != nil {
+		pvc := &v1.PersistentVolumeClaim{}
+		if err := r.getOwnedResource(ctx, limitadorObj.Namespace, limitador.PVCName(limitadorObj), pvc); err != nil {
+			return nil, nil, err
+		}
+		if !pvc.CreationTimestamp.IsZero() {
+			resources.PVC = &limitadorv1alpha1.PVCStatusSummary{
+				Phase:      string(pvc.Status.Phase),
+				VolumeName: pvc.Spec.VolumeName,
+			}
+		}
+	}
+
+	pods := &v1.PodList{}
+	if err := r.Client().List(ctx, pods, client.InNamespace(limitadorObj.Namespace), client.MatchingLabels(limitador.DeploymentLabels(limitadorObj))); err != nil {
+		return nil, nil, err
+	}
+	resources.Pods = podStatusSummaries(pods)
+
+	return resources, service, nil
+}
+
+// getOwnedResource fetches obj into out, leaving out zero-valued when it does not exist yet —
+// a resource not having been created is not itself an error while a Limitador is being rolled out.
+func (r *LimitadorReconciler) getOwnedResource(ctx context.Context, namespace, name string, out client.Object) error {
+	if err := r.Client().Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, out); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func deploymentStatusSummary(deployment *appsv1.Deployment) *limitadorv1alpha1.DeploymentStatusSummary {
+	image := ""
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	conditions := make([]string, 0, len(deployment.Status.Conditions))
+	for _, condition := range deployment.Status.Conditions {
+		conditions = append(conditions, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+	}
+
+	return &limitadorv1alpha1.DeploymentStatusSummary{
+		Image:             image,
+		Replicas:          deployment.Status.Replicas,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		Conditions:        conditions,
+	}
+}
+
+func serviceStatusSummary(service *v1.Service) *limitadorv1alpha1.ServiceStatusSummary {
+	ports := make([]int32, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, port.Port)
+	}
+
+	return &limitadorv1alpha1.ServiceStatusSummary{
+		ClusterIP: service.Spec.ClusterIP,
+		Ports:     ports,
+	}
+}
+
+func podStatusSummaries(pods *v1.PodList) []limitadorv1alpha1.PodStatusSummary {
+	summaries := make([]limitadorv1alpha1.PodStatusSummary, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		containers := make([]limitadorv1alpha1.ContainerStatusSummary, 0, len(pod.Status.ContainerStatuses))
+		var restarts int32
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			containers = append(containers, limitadorv1alpha1.ContainerStatusSummary{
+				Name:         containerStatus.Name,
+				Ready:        containerStatus.Ready,
+				RestartCount: containerStatus.RestartCount,
+			})
+			restarts += containerStatus.RestartCount
+		}
+
+		summaries = append(summaries, limitadorv1alpha1.PodStatusSummary{
+			Name:         pod.Name,
+			Phase:        string(pod.Status.Phase),
+			RestartCount: restarts,
+			Containers:   containers,
+		})
+	}
+	return summaries
+}
+
+func hashConfigMapData(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// limitadorServiceStatus builds the LimitadorService status, or nil when the Service doesn't
+// exist yet, giving users the host/ports to reach the Limitador instance at.
+func limitadorServiceStatus(service *v1.Service) *limitadorv1alpha1.LimitadorService {
+	if service == nil {
+		return nil
+	}
+
+	var ports limitadorv1alpha1.Ports
+	for _, port := range service.Spec.Ports {
+		switch port.Name {
+		case "grpc":
+			ports.GRPC = port.Port
+		case "http":
+			ports.HTTP = port.Port
+		}
+	}
+
+	return &limitadorv1alpha1.LimitadorService{
+		Host:  service.Spec.ClusterIP,
+		Ports: ports,
+	}
+}
+
+// conditionReadyType is the Type of the single condition reconcileStatus maintains, reusing
+// v1.NodeCondition as a generic condition shape rather than adding a custom one.
+const conditionReadyType = v1.NodeConditionType("Ready")
+
+// readyCondition derives the Ready condition from the outcome of reconcileSpec and the owned
+// Deployment's availability, preserving the previous LastTransitionTime when the status hasn't
+// actually changed so that updates aren't forced every reconcile. desiredReplicas is
+// Spec.Replicas: when the user has deliberately scaled down to 0, having no available replicas is
+// the intended, healthy state rather than a failure.
+func readyCondition(previous []v1.NodeCondition, specErr error, deployment *limitadorv1alpha1.DeploymentStatusSummary, desiredReplicas *int) []v1.NodeCondition {
+	var status v1.ConditionStatus
+	var reason, message string
+
+	switch {
+	case specErr != nil:
+		status = v1.ConditionFalse
+		reason = "ReconcileError"
+		message = specErr.Error()
+	case desiredReplicas != nil && *desiredReplicas == 0:
+		status = v1.ConditionTrue
+		reason = "ScaledToZero"
+		message = "the Limitador Deployment is scaled to 0 replicas, as requested"
+	case deployment == nil || deployment.AvailableReplicas == 0:
+		status = v1.ConditionFalse
+		reason = "DeploymentNotAvailable"
+		message = "the Limitador Deployment has no available replicas"
+	default:
+		status = v1.ConditionTrue
+		reason = "DeploymentAvailable"
+		message = "the Limitador Deployment has available replicas"
+	}
+
+	lastTransitionTime := metav1.Now()
+	for _, condition := range previous {
+		if condition.Type == conditionReadyType && condition.Status == status {
+			lastTransitionTime = condition.LastTransitionTime
+			break
+		}
+	}
+
+	return []v1.NodeCondition{
+		{
+			Type:               conditionReadyType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: lastTransitionTime,
+		},
+	}
+}
+
+func equalResourcesStatus(a, b *limitadorv1alpha1.ResourcesStatus) bool {
+	return reflect.DeepEqual(a, b)
+}
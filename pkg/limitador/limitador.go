@@ -0,0 +1,233 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitador
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+const (
+	// LimitadorConfigFileName is the key, within the limits ConfigMap's Data, holding the rate limit definitions.
+	LimitadorConfigFileName = "limits.yaml"
+
+	// RedisSecretHashAnnotation is set on the Deployment's pod template whenever the storage backend reads its
+	// connection details from a Secret, so that rotating that Secret triggers a rolling restart.
+	RedisSecretHashAnnotation = "limitador.kuadrant.io/redis-secret-hash"
+
+	limitadorPortGRPC = 8081
+	limitadorPortHTTP = 8080
+)
+
+// DeploymentStorageOptions carries everything a storage backend needs to contribute to the
+// Deployment: the CLI command, any extra volumes/mounts it requires and annotations that should
+// be stamped onto the pod template to force a rollout when the backend's config changes.
+type DeploymentStorageOptions struct {
+	Command            []string
+	Volumes            []v1.Volume
+	VolumeMounts       []v1.VolumeMount
+	DeploymentStrategy appsv1.DeploymentStrategy
+	PodAnnotations     map[string]string
+}
+
+// DeploymentOptions carries the final set of options used to render the limitador Deployment.
+type DeploymentOptions struct {
+	Command            []string
+	Volumes            []v1.Volume
+	VolumeMounts       []v1.VolumeMount
+	EnvVar             []v1.EnvVar
+	DeploymentStrategy appsv1.DeploymentStrategy
+	PodAnnotations     map[string]string
+}
+
+func DeploymentName(limitadorObj *limitadorv1alpha1.Limitador) string {
+	return fmt.Sprintf("limitador-%s", limitadorObj.Name)
+}
+
+func ServiceName(limitadorObj *limitadorv1alpha1.Limitador) string {
+	return fmt.Sprintf("limitador-%s", limitadorObj.Name)
+}
+
+func PVCName(limitadorObj *limitadorv1alpha1.Limitador) string {
+	return fmt.Sprintf("limitador-%s", limitadorObj.Name)
+}
+
+func PodDisruptionBudgetName(limitadorObj *limitadorv1alpha1.Limitador) string {
+	return fmt.Sprintf("limitador-%s", limitadorObj.Name)
+}
+
+func LimitsConfigMapName(limitadorObj *limitadorv1alpha1.Limitador) string {
+	return fmt.Sprintf("limitador-%s-config", limitadorObj.Name)
+}
+
+func DeploymentLabels(limitadorObj *limitadorv1alpha1.Limitador) map[string]string {
+	return map[string]string{
+		"app":       "limitador",
+		"limitador": limitadorObj.Name,
+	}
+}
+
+// Deployment builds the desired Deployment for a Limitador object given the resolved
+// DeploymentOptions (command, volumes and env vars contributed by the configured storage backend).
+func Deployment(limitadorObj *limitadorv1alpha1.Limitador, options DeploymentOptions) *appsv1.Deployment {
+	labels := DeploymentLabels(limitadorObj)
+
+	podAnnotations := map[string]string{}
+	for k, v := range options.PodAnnotations {
+		podAnnotations[k] = v
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DeploymentName(limitadorObj),
+			Namespace: limitadorObj.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Strategy: options.DeploymentStrategy,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: podAnnotations,
+				},
+				Spec: v1.PodSpec{
+					Volumes: options.Volumes,
+					Containers: []v1.Container{
+						{
+							Name:         "limitador",
+							Image:        limitadorImage(limitadorObj),
+							Command:      []string{"limitador-server"},
+							Args:         options.Command,
+							Env:          options.EnvVar,
+							VolumeMounts: options.VolumeMounts,
+							Ports: []v1.ContainerPort{
+								{Name: "grpc", ContainerPort: limitadorPortGRPC},
+								{Name: "http", ContainerPort: limitadorPortHTTP},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func limitadorImage(limitadorObj *limitadorv1alpha1.Limitador) string {
+	if limitadorObj.Spec.Image != "" {
+		return limitadorObj.Spec.Image
+	}
+	return "quay.io/kuadrant/limitador"
+}
+
+func Service(limitadorObj *limitadorv1alpha1.Limitador) *v1.Service {
+	labels := DeploymentLabels(limitadorObj)
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceName(limitadorObj),
+			Namespace: limitadorObj.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: labels,
+			Ports: []v1.ServicePort{
+				{Name: "grpc", Port: limitadorPortGRPC},
+				{Name: "http", Port: limitadorPortHTTP},
+			},
+		},
+	}
+}
+
+func PVC(limitadorObj *limitadorv1alpha1.Limitador) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PVCName(limitadorObj),
+			Namespace: limitadorObj.Namespace,
+			Labels:    DeploymentLabels(limitadorObj),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+		},
+	}
+}
+
+func PodDisruptionBudget(limitadorObj *limitadorv1alpha1.Limitador) *policyv1.PodDisruptionBudget {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: DeploymentLabels(limitadorObj)},
+	}
+
+	if limitadorObj.Spec.PodDisruptionBudget != nil {
+		spec.MinAvailable = limitadorObj.Spec.PodDisruptionBudget.MinAvailable
+		spec.MaxUnavailable = limitadorObj.Spec.PodDisruptionBudget.MaxUnavailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodDisruptionBudgetName(limitadorObj),
+			Namespace: limitadorObj.Namespace,
+			Labels:    DeploymentLabels(limitadorObj),
+		},
+		Spec: spec,
+	}
+}
+
+func ValidatePDB(pdb *policyv1.PodDisruptionBudget) error {
+	if pdb.Spec.MinAvailable != nil && pdb.Spec.MaxUnavailable != nil {
+		return fmt.Errorf("only one of minAvailable or maxUnavailable can be set")
+	}
+	return nil
+}
+
+// LimitsConfigMap builds the desired ConfigMap holding the rate limit definitions. The limits are
+// canonicalized before being serialized, so the on-disk file limitador reads is deterministic
+// regardless of the order limits were declared in the spec, and the ConfigMap is stamped with a
+// fingerprint of that canonical form so genuine changes can be told apart from re-orderings.
+func LimitsConfigMap(limitadorObj *limitadorv1alpha1.Limitador) (*v1.ConfigMap, error) {
+	canonicalLimits := CanonicalizeRateLimits(limitadorObj.Spec.Limits)
+	if canonicalLimits == nil {
+		canonicalLimits = []limitadorv1alpha1.RateLimit{}
+	}
+
+	serialized, err := yaml.Marshal(canonicalLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := LimitsFingerprint(limitadorObj.Spec.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        LimitsConfigMapName(limitadorObj),
+			Namespace:   limitadorObj.Namespace,
+			Labels:      DeploymentLabels(limitadorObj),
+			Annotations: map[string]string{LimitsHashAnnotation: hash},
+		},
+		Data: map[string]string{
+			LimitadorConfigFileName: string(serialized),
+		},
+	}, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitador
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+// LimitsHashAnnotation is set on both the limits ConfigMap and the Deployment's pod template with
+// a fingerprint of the canonical limits, so genuine changes trigger a rollout while re-orderings
+// of an otherwise identical limit set do not.
+const LimitsHashAnnotation = "limitador.kuadrant.io/limits-hash"
+
+// CanonicalizeRateLimits returns a copy of limits sorted into a stable order, so that two limit
+// sets differing only in element order compare equal and serialize identically. Each limit's
+// Conditions and Variables are sorted too, for the same reason.
+func CanonicalizeRateLimits(limits []limitadorv1alpha1.RateLimit) []limitadorv1alpha1.RateLimit {
+	if limits == nil {
+		return nil
+	}
+
+	canonical := make([]limitadorv1alpha1.RateLimit, len(limits))
+	for i, limit := range limits {
+		canonical[i] = *limit.DeepCopy()
+		sort.Strings(canonical[i].Conditions)
+		sort.Strings(canonical[i].Variables)
+	}
+
+	sort.Slice(canonical, func(i, j int) bool {
+		return rateLimitSortKey(canonical[i]) < rateLimitSortKey(canonical[j])
+	})
+
+	return canonical
+}
+
+// rateLimitSortKey builds a stable, comparable key from a RateLimit's identifying fields so
+// canonicalized limit sets always sort into the same order regardless of the order limits were
+// originally declared in.
+func rateLimitSortKey(limit limitadorv1alpha1.RateLimit) string {
+	return fmt.Sprintf("%s\x00%010d\x00%010d\x00%q\x00%q", limit.Namespace, limit.MaxValue, limit.Seconds, limit.Conditions, limit.Variables)
+}
+
+// LimitsFingerprint returns a stable hash of the canonical form of limits, suitable for use as an
+// annotation value to detect genuine changes to the limit set.
+func LimitsFingerprint(limits []limitadorv1alpha1.RateLimit) (string, error) {
+	canonical, err := yaml.Marshal(CanonicalizeRateLimits(limits))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,58 @@
+package limitador
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+func TestCanonicalizeRateLimits(t *testing.T) {
+	limitA := limitadorv1alpha1.RateLimit{Namespace: "a", MaxValue: 10, Seconds: 60, Conditions: []string{"b == 1", "a == 1"}, Variables: []string{"y", "x"}}
+	limitB := limitadorv1alpha1.RateLimit{Namespace: "b", MaxValue: 5, Seconds: 60}
+
+	t.Run("order-insensitive", func(subT *testing.T) {
+		shuffledOne := CanonicalizeRateLimits([]limitadorv1alpha1.RateLimit{limitA, limitB})
+		shuffledTwo := CanonicalizeRateLimits([]limitadorv1alpha1.RateLimit{limitB, limitA})
+		assert.DeepEqual(subT, shuffledOne, shuffledTwo)
+	})
+
+	t.Run("sorts each limit's conditions and variables", func(subT *testing.T) {
+		canonical := CanonicalizeRateLimits([]limitadorv1alpha1.RateLimit{limitA})
+		assert.DeepEqual(subT, canonical[0].Conditions, []string{"a == 1", "b == 1"})
+		assert.DeepEqual(subT, canonical[0].Variables, []string{"x", "y"})
+	})
+
+	t.Run("duplicate limits are preserved, not deduplicated", func(subT *testing.T) {
+		canonical := CanonicalizeRateLimits([]limitadorv1alpha1.RateLimit{limitB, limitB})
+		assert.Equal(subT, len(canonical), 2)
+	})
+
+	t.Run("nil input returns nil", func(subT *testing.T) {
+		assert.Assert(subT, CanonicalizeRateLimits(nil) == nil)
+	})
+}
+
+func TestLimitsFingerprint(t *testing.T) {
+	limitA := limitadorv1alpha1.RateLimit{Namespace: "a", MaxValue: 10, Seconds: 60}
+	limitB := limitadorv1alpha1.RateLimit{Namespace: "b", MaxValue: 5, Seconds: 60}
+
+	t.Run("is stable across re-orderings of an identical limit set", func(subT *testing.T) {
+		hashOne, err := LimitsFingerprint([]limitadorv1alpha1.RateLimit{limitA, limitB})
+		assert.NilError(subT, err)
+		hashTwo, err := LimitsFingerprint([]limitadorv1alpha1.RateLimit{limitB, limitA})
+		assert.NilError(subT, err)
+		assert.Equal(subT, hashOne, hashTwo)
+	})
+
+	t.Run("changes when a limit actually changes", func(subT *testing.T) {
+		hashOne, err := LimitsFingerprint([]limitadorv1alpha1.RateLimit{limitA})
+		assert.NilError(subT, err)
+		changed := limitA
+		changed.MaxValue = 11
+		hashTwo, err := LimitsFingerprint([]limitadorv1alpha1.RateLimit{changed})
+		assert.NilError(subT, err)
+		assert.Assert(subT, hashOne != hashTwo)
+	})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitador
+
+import (
+	"context"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+// MemcachedDeploymentOptions reads the memcached connection URL from the Secret referenced by
+// memcachedObj and builds the DeploymentStorageOptions for the memcached backend.
+func MemcachedDeploymentOptions(ctx context.Context, cl client.Client, namespace string, memcachedObj limitadorv1alpha1.Memcached) (DeploymentStorageOptions, error) {
+	_, hash, err := readRedisSecretURL(ctx, cl, namespace, memcachedObj.ConfigSecretRef)
+	if err != nil {
+		return DeploymentStorageOptions{}, err
+	}
+
+	command := []string{"memcached", "$(LIMITADOR_OPERATOR_MEMCACHED_URL)"}
+
+	if memcachedObj.FlushPeriod != nil {
+		command = append(command, "--flush-period", strconv.Itoa(*memcachedObj.FlushPeriod))
+	}
+	if memcachedObj.MaxCached != nil {
+		command = append(command, "--max-cached", strconv.Itoa(*memcachedObj.MaxCached))
+	}
+
+	return DeploymentStorageOptions{
+		Command:        command,
+		PodAnnotations: map[string]string{RedisSecretHashAnnotation: hash},
+	}, nil
+}
+
+func memcachedEnvVar(secretRef *v1.ObjectReference) ([]v1.EnvVar, error) {
+	if secretRef == nil {
+		return nil, unexpectedSpecTypeError("memcached", secretRef)
+	}
+
+	return []v1.EnvVar{
+		{
+			Name: "LIMITADOR_OPERATOR_MEMCACHED_URL",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: secretRef.Name},
+					Key:                  redisURLSecretKey,
+				},
+			},
+		},
+	}, nil
+}
+
+type memcachedBackend struct{}
+
+func (memcachedBackend) Name() string { return "memcached" }
+
+func (memcachedBackend) Validate(spec interface{}) error {
+	if _, ok := spec.(*limitadorv1alpha1.Memcached); !ok {
+		return unexpectedSpecTypeError("memcached", spec)
+	}
+	return nil
+}
+
+func (memcachedBackend) DeploymentOptions(ctx context.Context, cl client.Client, namespace string, spec interface{}) (DeploymentStorageOptions, error) {
+	memcachedObj, ok := spec.(*limitadorv1alpha1.Memcached)
+	if !ok {
+		return DeploymentStorageOptions{}, unexpectedSpecTypeError("memcached", spec)
+	}
+	return MemcachedDeploymentOptions(ctx, cl, namespace, *memcachedObj)
+}
+
+func (memcachedBackend) EnvVars(_ context.Context, _ client.Client, _ string, spec interface{}) ([]v1.EnvVar, error) {
+	memcachedObj, ok := spec.(*limitadorv1alpha1.Memcached)
+	if !ok {
+		return nil, unexpectedSpecTypeError("memcached", spec)
+	}
+	return memcachedEnvVar(memcachedObj.ConfigSecretRef)
+}
+
+func init() {
+	RegisterStorageBackend("Memcached", memcachedBackend{})
+}
@@ -0,0 +1,135 @@
+package limitador
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"gotest.tools/assert"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+	"github.com/kuadrant/limitador-operator/pkg/log"
+)
+
+func TestMemcachedDeploymentOptions(t *testing.T) {
+	var (
+		namespace = "some-ns"
+	)
+
+	logger := log.Log.WithName("memcached_deployment_test")
+	baseCtx := context.Background()
+	ctx := logr.NewContext(baseCtx, logger)
+
+	clientFactory := func(subT *testing.T, objs []client.Object) client.Client {
+		s := scheme.Scheme
+		err := appsv1.AddToScheme(s)
+		assert.NilError(subT, err)
+
+		clBuilder := fake.NewClientBuilder()
+		return clBuilder.WithObjects(objs...).Build()
+	}
+
+	t.Run("memcached secretRef missing", func(subT *testing.T) {
+		cl := clientFactory(subT, nil)
+		emptyMemcachedObj := limitadorv1alpha1.Memcached{}
+		_, err := MemcachedDeploymentOptions(ctx, cl, namespace, emptyMemcachedObj)
+		assert.Error(subT, err, "there's no ConfigSecretRef set")
+	})
+
+	t.Run("memcached secret resource missing", func(subT *testing.T) {
+		cl := clientFactory(subT, nil)
+		memcachedObj := limitadorv1alpha1.Memcached{
+			ConfigSecretRef: &v1.ObjectReference{Name: "notexisting", Namespace: namespace},
+		}
+		_, err := MemcachedDeploymentOptions(ctx, cl, namespace, memcachedObj)
+		assert.Assert(subT, errors.IsNotFound(err))
+	})
+
+	t.Run("memcached secret does not have URL field", func(subT *testing.T) {
+		emptySecret := &v1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: "memcachedSecret", Namespace: namespace},
+			StringData: map[string]string{},
+			Data:       map[string][]byte{},
+			Type:       v1.SecretTypeOpaque,
+		}
+		cl := clientFactory(subT, []client.Object{emptySecret})
+		memcachedObj := limitadorv1alpha1.Memcached{
+			ConfigSecretRef: &v1.ObjectReference{Name: "memcachedSecret", Namespace: namespace},
+		}
+		_, err := MemcachedDeploymentOptions(ctx, cl, namespace, memcachedObj)
+		assert.Error(subT, err, "the storage config Secret doesn't have the `URL` field")
+	})
+
+	t.Run("basic memcached options", func(subT *testing.T) {
+		memcachedSecret := &v1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: "memcachedSecret", Namespace: namespace},
+			StringData: map[string]string{"URL": "memcached://example.com:11211"},
+			Type:       v1.SecretTypeOpaque,
+		}
+		memcachedSecret.Data = helperGetSecretDataFromStringData(memcachedSecret.StringData)
+
+		cl := clientFactory(subT, []client.Object{memcachedSecret})
+		memcachedObj := limitadorv1alpha1.Memcached{
+			ConfigSecretRef: &v1.ObjectReference{Name: "memcachedSecret", Namespace: namespace},
+		}
+		options, err := MemcachedDeploymentOptions(ctx, cl, namespace, memcachedObj)
+		assert.NilError(subT, err)
+		assert.DeepEqual(subT, options,
+			DeploymentStorageOptions{
+				Command:        []string{"memcached", "$(LIMITADOR_OPERATOR_MEMCACHED_URL)"},
+				PodAnnotations: map[string]string{RedisSecretHashAnnotation: hashURL("memcached://example.com:11211")},
+			},
+		)
+	})
+
+	t.Run("memcached options with flags", func(subT *testing.T) {
+		memcachedSecret := &v1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: "memcachedSecret", Namespace: namespace},
+			StringData: map[string]string{"URL": "memcached://example.com:11211"},
+			Type:       v1.SecretTypeOpaque,
+		}
+		memcachedSecret.Data = helperGetSecretDataFromStringData(memcachedSecret.StringData)
+
+		cl := clientFactory(subT, []client.Object{memcachedSecret})
+		memcachedObj := limitadorv1alpha1.Memcached{
+			ConfigSecretRef: &v1.ObjectReference{Name: "memcachedSecret", Namespace: namespace},
+			FlushPeriod:     &[]int{3}[0],
+			MaxCached:       &[]int{4}[0],
+		}
+		options, err := MemcachedDeploymentOptions(ctx, cl, namespace, memcachedObj)
+		assert.NilError(subT, err)
+		assert.DeepEqual(subT, options,
+			DeploymentStorageOptions{
+				Command: []string{
+					"memcached",
+					"$(LIMITADOR_OPERATOR_MEMCACHED_URL)",
+					"--flush-period", "3",
+					"--max-cached", "4",
+				},
+				PodAnnotations: map[string]string{RedisSecretHashAnnotation: hashURL("memcached://example.com:11211")},
+			},
+		)
+	})
+
+	t.Run("registered under the storage registry", func(subT *testing.T) {
+		backend, spec, ok := ResolveStorageBackend(&limitadorv1alpha1.Storage{
+			Memcached: &limitadorv1alpha1.Memcached{
+				ConfigSecretRef: &v1.ObjectReference{Name: "memcachedSecret", Namespace: namespace},
+			},
+		})
+		assert.Assert(subT, ok)
+		assert.Equal(subT, backend.Name(), "memcached")
+		assert.NilError(subT, backend.Validate(spec))
+	})
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitador
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+const redisURLSecretKey = "URL"
+
+// RedisDeploymentOptions reads the Redis connection URL from the Secret referenced by redisObj and
+// builds the DeploymentStorageOptions for the plain (non-cached) redis backend. TLS is controlled
+// solely by the URL's scheme (rediss://), which is the Secret's responsibility, not the
+// operator's — see DeploymentEnvVar.
+func RedisDeploymentOptions(ctx context.Context, cl client.Client, namespace string, redisObj limitadorv1alpha1.Redis) (DeploymentStorageOptions, error) {
+	_, hash, err := readRedisSecretURL(ctx, cl, namespace, redisObj.ConfigSecretRef)
+	if err != nil {
+		return DeploymentStorageOptions{}, err
+	}
+
+	return DeploymentStorageOptions{
+		Command:        []string{"redis", "$(LIMITADOR_OPERATOR_REDIS_URL)"},
+		PodAnnotations: map[string]string{RedisSecretHashAnnotation: hash},
+	}, nil
+}
+
+// RedisCachedDeploymentOptions reads the Redis connection URL from the Secret referenced by redisObj and
+// builds the DeploymentStorageOptions for the redis backend with an in-memory cache in front of it.
+func RedisCachedDeploymentOptions(ctx context.Context, cl client.Client, namespace string, redisObj limitadorv1alpha1.RedisCached) (DeploymentStorageOptions, error) {
+	_, hash, err := readRedisSecretURL(ctx, cl, namespace, redisObj.ConfigSecretRef)
+	if err != nil {
+		return DeploymentStorageOptions{}, err
+	}
+
+	command := []string{"redis_cached", "$(LIMITADOR_OPERATOR_REDIS_URL)"}
+
+	if redisObj.Options != nil {
+		if redisObj.Options.TTL != nil {
+			command = append(command, "--ttl", strconv.Itoa(*redisObj.Options.TTL))
+		}
+		if redisObj.Options.Ratio != nil {
+			command = append(command, "--ratio", strconv.Itoa(*redisObj.Options.Ratio))
+		}
+		if redisObj.Options.FlushPeriod != nil {
+			command = append(command, "--flush-period", strconv.Itoa(*redisObj.Options.FlushPeriod))
+		}
+		if redisObj.Options.MaxCached != nil {
+			command = append(command, "--max-cached", strconv.Itoa(*redisObj.Options.MaxCached))
+		}
+	}
+
+	return DeploymentStorageOptions{
+		Command:        command,
+		PodAnnotations: map[string]string{RedisSecretHashAnnotation: hash},
+	}, nil
+}
+
+// readRedisSecretURL fetches the referenced Secret and returns its URL field along with a stable
+// hash of it, used to force a rolling restart whenever the Secret's content is rotated.
+func readRedisSecretURL(ctx context.Context, cl client.Client, namespace string, secretRef *v1.ObjectReference) (string, string, error) {
+	if secretRef == nil {
+		return "", "", fmt.Errorf("there's no ConfigSecretRef set")
+	}
+
+	secret := &v1.Secret{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return "", "", err
+	}
+
+	url, ok := secret.Data[redisURLSecretKey]
+	if !ok {
+		return "", "", fmt.Errorf("the storage config Secret doesn't have the `%s` field", redisURLSecretKey)
+	}
+
+	sum := sha256.Sum256(url)
+	return string(url), hex.EncodeToString(sum[:]), nil
+}
+
+// DeploymentEnvVar builds the LIMITADOR_OPERATOR_REDIS_URL env var for the given Secret. The
+// value is always sourced via a SecretKeyRef, TLS or not: redis URLs routinely embed credentials,
+// and limitador-server decides whether to speak TLS purely from the URL's scheme, so there is
+// nothing for the operator to resolve or rewrite here. Enabling TLS is a matter of the Secret's
+// URL field already using the rediss:// scheme.
+func DeploymentEnvVar(secretRef *v1.ObjectReference) ([]v1.EnvVar, error) {
+	if secretRef == nil {
+		return nil, fmt.Errorf("there's no ConfigSecretRef set")
+	}
+
+	return []v1.EnvVar{
+		{
+			Name: "LIMITADOR_OPERATOR_REDIS_URL",
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: secretRef.Name},
+					Key:                  redisURLSecretKey,
+				},
+			},
+		},
+	}, nil
+}
+
+// InMemoryDeploymentOptions builds the DeploymentStorageOptions for the default, in-memory backend.
+func InMemoryDeploymentOptions() (DeploymentStorageOptions, error) {
+	return DeploymentStorageOptions{Command: []string{"memory"}}, nil
+}
+
+// DiskDeploymentOptions builds the DeploymentStorageOptions for the disk-backed storage.
+func DiskDeploymentOptions(limitadorObj *limitadorv1alpha1.Limitador, disk limitadorv1alpha1.DiskSpec) (DeploymentStorageOptions, error) {
+	command := []string{"disk"}
+	if disk.Optimize != "" {
+		command = append(command, "--optimize", disk.Optimize)
+	}
+
+	return DeploymentStorageOptions{
+		Command: command,
+		Volumes: []v1.Volume{
+			{
+				Name: "limitador-storage",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: PVCName(limitadorObj)},
+				},
+			},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: "limitador-storage", MountPath: "/opt/kuadrant/limitador/data"},
+		},
+	}, nil
+}
+
+// DeploymentCommand builds the full limitador-server command line for the given storage options.
+func DeploymentCommand(limitadorObj *limitadorv1alpha1.Limitador, storageOptions DeploymentStorageOptions) []string {
+	return append([]string{"0.0.0.0:8080", "0.0.0.0:8081", fmt.Sprintf("/home/limitador/etc/%s", LimitadorConfigFileName)}, storageOptions.Command...)
+}
+
+// DeploymentVolumes returns the Volumes contributed by the configured storage backend, plus the
+// limits ConfigMap volume every limitador Deployment mounts.
+func DeploymentVolumes(limitadorObj *limitadorv1alpha1.Limitador, storageOptions DeploymentStorageOptions) []v1.Volume {
+	volumes := []v1.Volume{
+		{
+			Name: "config-file",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: LimitsConfigMapName(limitadorObj)},
+				},
+			},
+		},
+	}
+	return append(volumes, storageOptions.Volumes...)
+}
+
+// DeploymentVolumeMounts returns the VolumeMounts contributed by the configured storage backend,
+// plus the limits ConfigMap mount every limitador Deployment has.
+func DeploymentVolumeMounts(storageOptions DeploymentStorageOptions) []v1.VolumeMount {
+	mounts := []v1.VolumeMount{
+		{Name: "config-file", MountPath: "/home/limitador/etc"},
+	}
+	return append(mounts, storageOptions.VolumeMounts...)
+}
+
+type redisBackend struct{}
+
+func (redisBackend) Name() string { return "redis" }
+
+func (redisBackend) Validate(spec interface{}) error {
+	if _, ok := spec.(*limitadorv1alpha1.Redis); !ok {
+		return unexpectedSpecTypeError("redis", spec)
+	}
+	return nil
+}
+
+func (redisBackend) DeploymentOptions(ctx context.Context, cl client.Client, namespace string, spec interface{}) (DeploymentStorageOptions, error) {
+	redisObj, ok := spec.(*limitadorv1alpha1.Redis)
+	if !ok {
+		return DeploymentStorageOptions{}, unexpectedSpecTypeError("redis", spec)
+	}
+	return RedisDeploymentOptions(ctx, cl, namespace, *redisObj)
+}
+
+func (redisBackend) EnvVars(_ context.Context, _ client.Client, _ string, spec interface{}) ([]v1.EnvVar, error) {
+	redisObj, ok := spec.(*limitadorv1alpha1.Redis)
+	if !ok {
+		return nil, unexpectedSpecTypeError("redis", spec)
+	}
+	return DeploymentEnvVar(redisObj.ConfigSecretRef)
+}
+
+type redisCachedBackend struct{}
+
+func (redisCachedBackend) Name() string { return "redis-cached" }
+
+func (redisCachedBackend) Validate(spec interface{}) error {
+	if _, ok := spec.(*limitadorv1alpha1.RedisCached); !ok {
+		return unexpectedSpecTypeError("redis-cached", spec)
+	}
+	return nil
+}
+
+func (redisCachedBackend) DeploymentOptions(ctx context.Context, cl client.Client, namespace string, spec interface{}) (DeploymentStorageOptions, error) {
+	redisObj, ok := spec.(*limitadorv1alpha1.RedisCached)
+	if !ok {
+		return DeploymentStorageOptions{}, unexpectedSpecTypeError("redis-cached", spec)
+	}
+	return RedisCachedDeploymentOptions(ctx, cl, namespace, *redisObj)
+}
+
+func (redisCachedBackend) EnvVars(_ context.Context, _ client.Client, _ string, spec interface{}) ([]v1.EnvVar, error) {
+	redisObj, ok := spec.(*limitadorv1alpha1.RedisCached)
+	if !ok {
+		return nil, unexpectedSpecTypeError("redis-cached", spec)
+	}
+	return DeploymentEnvVar(redisObj.ConfigSecretRef)
+}
+
+func init() {
+	RegisterStorageBackend("Redis", redisBackend{})
+	RegisterStorageBackend("RedisCached", redisCachedBackend{})
+}
+
+func helperGetSecretDataFromStringData(stringData map[string]string) map[string][]byte {
+	data := make(map[string][]byte, len(stringData))
+	for k, v := range stringData {
+		data[k] = []byte(v)
+	}
+	return data
+}
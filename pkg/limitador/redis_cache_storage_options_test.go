@@ -2,6 +2,8 @@ package limitador
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -86,7 +88,8 @@ func TestRedisCachedDeploymentOptions(t *testing.T) {
 		assert.NilError(subT, err)
 		assert.DeepEqual(subT, options,
 			DeploymentStorageOptions{
-				Command: []string{"redis_cached", "$(LIMITADOR_OPERATOR_REDIS_URL)"},
+				Command:        []string{"redis_cached", "$(LIMITADOR_OPERATOR_REDIS_URL)"},
+				PodAnnotations: map[string]string{RedisSecretHashAnnotation: hashURL("redis://example.com:6379")},
 			},
 		)
 	})
@@ -122,7 +125,37 @@ func TestRedisCachedDeploymentOptions(t *testing.T) {
 					"--flush-period", "3",
 					"--max-cached", "4",
 				},
+				PodAnnotations: map[string]string{RedisSecretHashAnnotation: hashURL("redis://example.com:6379")},
 			},
 		)
 	})
+
+	t.Run("hash annotation changes when the secret URL rotates", func(subT *testing.T) {
+		redisObj := limitadorv1alpha1.RedisCached{
+			ConfigSecretRef: &v1.ObjectReference{Name: "redisSecret", Namespace: namespace},
+		}
+
+		oldSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "redisSecret", Namespace: namespace},
+			StringData: map[string]string{"URL": "redis://old.example.com:6379"},
+		}
+		oldSecret.Data = helperGetSecretDataFromStringData(oldSecret.StringData)
+		oldOptions, err := RedisCachedDeploymentOptions(ctx, clientFactory(subT, []client.Object{oldSecret}), namespace, redisObj)
+		assert.NilError(subT, err)
+
+		newSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "redisSecret", Namespace: namespace},
+			StringData: map[string]string{"URL": "redis://new.example.com:6379"},
+		}
+		newSecret.Data = helperGetSecretDataFromStringData(newSecret.StringData)
+		newOptions, err := RedisCachedDeploymentOptions(ctx, clientFactory(subT, []client.Object{newSecret}), namespace, redisObj)
+		assert.NilError(subT, err)
+
+		assert.Assert(subT, oldOptions.PodAnnotations[RedisSecretHashAnnotation] != newOptions.PodAnnotations[RedisSecretHashAnnotation])
+	})
+}
+
+func hashURL(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
 }
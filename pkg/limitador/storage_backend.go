@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitador
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	limitadorv1alpha1 "github.com/kuadrant/limitador-operator/api/v1alpha1"
+)
+
+// StorageBackend is implemented by every storage option limitador can be deployed with. Backends
+// are registered against the name of the field they occupy on limitadorv1alpha1.Storage, which
+// lets out-of-tree operators add new ones without having to patch LimitadorReconciler.
+type StorageBackend interface {
+	// Name identifies the backend, e.g. for log messages.
+	Name() string
+	// Validate checks the backend's portion of the Storage spec (the value of the Storage field
+	// this backend is registered under) before it is used to build a Deployment.
+	Validate(spec interface{}) error
+	// DeploymentOptions builds the command, volumes and annotations this backend contributes to
+	// the limitador Deployment.
+	DeploymentOptions(ctx context.Context, cl client.Client, namespace string, spec interface{}) (DeploymentStorageOptions, error)
+	// EnvVars builds any environment variables the backend's command line references. It takes
+	// the same ctx/cl/namespace as DeploymentOptions; the in-tree backends do not use them and
+	// point at the referenced Secret with a SecretKeyRef instead of reading it.
+	EnvVars(ctx context.Context, cl client.Client, namespace string, spec interface{}) ([]v1.EnvVar, error)
+}
+
+var storageBackends = map[string]StorageBackend{}
+
+// RegisterStorageBackend registers a StorageBackend under the name of the limitadorv1alpha1.Storage
+// field it handles (e.g. "Redis", "RedisCached", "Memcached"). Intended to be called from an
+// init() function of the package implementing the backend.
+func RegisterStorageBackend(storageField string, backend StorageBackend) {
+	storageBackends[storageField] = backend
+}
+
+// ResolveStorageBackend walks the fields of storage and returns the registered StorageBackend for
+// the first one that is set, together with that field's value. ok is false when storage has no
+// field set (callers should fall back to the in-memory backend) or none of the set fields has a
+// backend registered for it.
+func ResolveStorageBackend(storage *limitadorv1alpha1.Storage) (backend StorageBackend, spec interface{}, ok bool) {
+	if storage == nil {
+		return nil, nil, false
+	}
+
+	value := reflect.ValueOf(*storage)
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+
+		fieldName := valueType.Field(i).Name
+		if backend, registered := storageBackends[fieldName]; registered {
+			return backend, field.Interface(), true
+		}
+	}
+
+	return nil, nil, false
+}
+
+func unexpectedSpecTypeError(backend string, spec interface{}) error {
+	return fmt.Errorf("%s backend: unexpected spec type %T", backend, spec)
+}
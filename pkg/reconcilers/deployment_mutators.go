@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Red Hat.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcilers
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// DeploymentMutateFn mutates the in-cluster Deployment towards the desired state, returning
+// whether the existing Deployment was changed.
+type DeploymentMutateFn func(desired, existing *appsv1.Deployment) bool
+
+// managedAnnotationPrefix is the namespace every pod template annotation DeploymentAnnotationsMutator
+// owns is stamped under, so it can tell its own stale keys (e.g. a leftover RedisSecretHashAnnotation
+// after switching storage backends) apart from annotations set by some other actor.
+const managedAnnotationPrefix = "limitador.kuadrant.io/"
+
+// DeploymentAnnotationsMutator keeps the pod template annotations of the existing Deployment in
+// sync with the desired one. It is what makes hash annotations (e.g. RedisSecretHashAnnotation)
+// actually trigger a rolling restart when the underlying Secret they are derived from rotates, and
+// it removes its own managed keys that are no longer desired (e.g. redis-secret-hash after moving
+// off a Secret-backed storage backend) without touching annotations set by anyone else.
+func DeploymentAnnotationsMutator(desired, existing *appsv1.Deployment) bool {
+	updated := false
+
+	if existing.Spec.Template.Annotations == nil {
+		existing.Spec.Template.Annotations = map[string]string{}
+	}
+
+	for key, desiredValue := range desired.Spec.Template.Annotations {
+		if existingValue, ok := existing.Spec.Template.Annotations[key]; !ok || existingValue != desiredValue {
+			existing.Spec.Template.Annotations[key] = desiredValue
+			updated = true
+		}
+	}
+
+	for key := range existing.Spec.Template.Annotations {
+		if _, stillDesired := desired.Spec.Template.Annotations[key]; stillDesired {
+			continue
+		}
+		if strings.HasPrefix(key, managedAnnotationPrefix) {
+			delete(existing.Spec.Template.Annotations, key)
+			updated = true
+		}
+	}
+
+	return updated
+}